@@ -1,8 +1,46 @@
 package reactor
 
-// ReadCallback <- func(interface{})
-// WriteCallback <- func(interface{}, interface{})
-// BindingFunc <- func(interface{}) interface{}
+import (
+	"context"
+)
+
+// ReadCallbackCtx is a ReadCallback that additionally receives a
+// context.Context, so it can observe cancellation when the Trigger (or
+// subsystem) that owns it is shut down.
+type ReadCallbackCtx func(ctx context.Context, v interface{})
+
+// WithContext binds ctx to r, producing a plain ReadCallback suitable for
+// AddReadCallback. The returned callback checks ctx before running r, so a
+// canceled ctx aborts the callback instead of invoking r.
+func (r ReadCallbackCtx) WithContext(ctx context.Context) ReadCallback {
+	return func(v interface{}) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			r(ctx, v)
+		}
+	}
+}
+
+// WriteCallbackCtx is a WriteCallback that additionally receives a
+// context.Context, so it can observe cancellation when the Trigger (or
+// subsystem) that owns it is shut down.
+type WriteCallbackCtx func(ctx context.Context, prev, v interface{})
+
+// WithContext binds ctx to w, producing a plain WriteCallback suitable for
+// AddWriteCallback. The returned callback checks ctx before running w, so a
+// canceled ctx aborts the callback instead of invoking w.
+func (w WriteCallbackCtx) WithContext(ctx context.Context) WriteCallback {
+	return func(prev, v interface{}) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			w(ctx, prev, v)
+		}
+	}
+}
 
 
 func (r ReadCallback) Async() ReadCallback {
@@ -11,15 +49,17 @@ func (r ReadCallback) Async() ReadCallback {
 	}
 }
 
+// Concurrent dispatches r onto the shared default ConcurrentGroup: callbacks
+// are guaranteed to run in the order received, but not in parallel with one
+// another. Use ConcurrentIn to size or isolate the worker pool deliberately.
 func (r ReadCallback) Concurrent() ReadCallback {
-	conReadLock.Lock()
-		if conRead == nil {
-			conRead = make(chan conReadState, 100)
-			go runConcurrentRead()
-		}
-	conReadLock.Unlock()
+	return r.ConcurrentIn(defaultConcurrentGroup())
+}
+
+// ConcurrentIn dispatches r onto g instead of the shared default group.
+func (r ReadCallback) ConcurrentIn(g *ConcurrentGroup) ReadCallback {
 	return func(v interface{}) {
-		conRead <- conReadState{v, r}
+		g.dispatch(func() { r(v) })
 	}
 }
 
@@ -38,15 +78,17 @@ func (w WriteCallback) Async() WriteCallback {
 	}
 }
 
+// Concurrent dispatches w onto the shared default ConcurrentGroup: callbacks
+// are guaranteed to run in the order received, but not in parallel with one
+// another. Use ConcurrentIn to size or isolate the worker pool deliberately.
 func (w WriteCallback) Concurrent() WriteCallback {
-	conWriteLock.Lock()
-		if conWrite == nil {
-			conWrite = make(chan conWriteState, 100)
-			go runConcurrentWrite()
-		}
-	conWriteLock.Unlock()
+	return w.ConcurrentIn(defaultConcurrentGroup())
+}
+
+// ConcurrentIn dispatches w onto g instead of the shared default group.
+func (w WriteCallback) ConcurrentIn(g *ConcurrentGroup) WriteCallback {
 	return func(prev, v interface{}) {
-		conWrite <- conWriteState{prev, v, w}
+		g.dispatch(func() { w(prev, v) })
 	}
 }
 
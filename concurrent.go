@@ -0,0 +1,293 @@
+package reactor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy controls what a ConcurrentGroup does when its queue is full
+// and a new callback is dispatched onto it.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the caller until space is available in the
+	// queue. This is the default, and matches the original unconfigurable
+	// behavior of Concurrent().
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued callback to make room
+	// for the incoming one.
+	OverflowDropOldest
+	// OverflowDropNewest discards the incoming callback, leaving the queue
+	// untouched.
+	OverflowDropNewest
+	// OverflowError discards the incoming callback and reports ErrQueueFull
+	// to the group's OnError, if set.
+	OverflowError
+)
+
+// ErrQueueFull is passed to a GroupConfig's OnError when OverflowError drops
+// a callback because the queue was full.
+var ErrQueueFull = errors.New("reactor: concurrent group queue full")
+
+// GroupConfig configures a ConcurrentGroup.
+type GroupConfig struct {
+	// Workers is the number of goroutines draining the group's queue.
+	// Defaults to 1 if not positive.
+	Workers int
+	// QueueSize is the number of pending callbacks the group will buffer.
+	// Defaults to 100 if not positive.
+	QueueSize int
+	// Overflow determines what happens once the queue is full.
+	Overflow OverflowPolicy
+	// OnError is called, if non-nil, whenever Overflow is OverflowError and
+	// a callback is dropped.
+	OnError func(error)
+}
+
+type conJob struct {
+	fn func()
+	submitted time.Time
+}
+
+// ConcurrentGroup is a bounded worker pool that ReadCallback.ConcurrentIn and
+// WriteCallback.ConcurrentIn dispatch onto. Triggers can share one group
+// deliberately, or each be given their own, instead of going through a
+// single hidden global queue; this mirrors how a singleflight/flightcontrol
+// group is created explicitly and handed to whichever callers want to share
+// it.
+type ConcurrentGroup struct {
+	cfg GroupConfig
+	queue chan conJob
+
+	startOnce sync.Once
+
+	mu sync.Mutex
+	hist latencyHistogram
+
+	dropped uint64
+	inFlight int64
+
+	wg sync.WaitGroup
+}
+
+// NewConcurrentGroup creates a ConcurrentGroup and starts its workers.
+func NewConcurrentGroup(cfg GroupConfig) *ConcurrentGroup {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+
+	g := &ConcurrentGroup{
+		cfg: cfg,
+		queue: make(chan conJob, cfg.QueueSize),
+		hist: newLatencyHistogram(),
+	}
+	g.startOnce.Do(func() {
+		for i := 0; i < cfg.Workers; i++ {
+			go g.run()
+		}
+	})
+	return g
+}
+
+func (g *ConcurrentGroup) run() {
+	for j := range g.queue {
+		atomic.AddInt64(&g.inFlight, 1)
+		j.fn()
+		atomic.AddInt64(&g.inFlight, -1)
+
+		g.mu.Lock()
+			g.hist.observe(time.Since(j.submitted))
+		g.mu.Unlock()
+
+		g.wg.Done()
+	}
+}
+
+// dispatch queues fn according to g's overflow policy.
+func (g *ConcurrentGroup) dispatch(fn func()) {
+	j := conJob{fn, time.Now()}
+
+	switch g.cfg.Overflow {
+	case OverflowDropNewest:
+		g.wg.Add(1)
+		select {
+		case g.queue <- j:
+		default:
+			g.wg.Done()
+			atomic.AddUint64(&g.dropped, 1)
+		}
+	case OverflowDropOldest:
+		g.wg.Add(1)
+		for {
+			select {
+			case g.queue <- j:
+				return
+			default:
+			}
+			select {
+			case <-g.queue:
+				// The job we just pulled off will never run; account for
+				// it the same way a dropped incoming job would be.
+				g.wg.Done()
+				atomic.AddUint64(&g.dropped, 1)
+			default:
+			}
+		}
+	case OverflowError:
+		g.wg.Add(1)
+		select {
+		case g.queue <- j:
+		default:
+			g.wg.Done()
+			atomic.AddUint64(&g.dropped, 1)
+			if g.cfg.OnError != nil {
+				g.cfg.OnError(ErrQueueFull)
+			}
+		}
+	default: // OverflowBlock
+		g.wg.Add(1)
+		g.queue <- j
+	}
+}
+
+// Submit queues fn onto g directly, following the same overflow policy as
+// callbacks dispatched via ReadCallback.ConcurrentIn/WriteCallback.ConcurrentIn.
+// It's exported so other packages, such as reactor/typed, can share a
+// ConcurrentGroup without going through those callback types.
+func (g *ConcurrentGroup) Submit(fn func()) {
+	g.dispatch(fn)
+}
+
+// GroupStats is a point-in-time snapshot of a ConcurrentGroup's load.
+type GroupStats struct {
+	QueueDepth int
+	Dropped uint64
+	InFlight int64
+	Latency LatencyHistogram
+}
+
+// Stats reports g's current queue depth, drop count, in-flight callback
+// count, and latency distribution.
+func (g *ConcurrentGroup) Stats() GroupStats {
+	g.mu.Lock()
+		lat := g.hist.snapshot()
+	g.mu.Unlock()
+
+	return GroupStats{
+		QueueDepth: len(g.queue),
+		Dropped: atomic.LoadUint64(&g.dropped),
+		InFlight: atomic.LoadInt64(&g.inFlight),
+		Latency: lat,
+	}
+}
+
+// Flush blocks until every callback queued on g so far has run, or ctx is
+// done, whichever happens first.
+func (g *ConcurrentGroup) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// latencyBuckets are the upper bounds used by latencyHistogram, in
+// ascending order. A callback slower than the last bucket falls into a
+// final, unbounded overflow bucket.
+var latencyBuckets = []time.Duration{
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+type latencyHistogram struct {
+	counts []uint64
+	count uint64
+	sum time.Duration
+}
+
+// newLatencyHistogram returns a latencyHistogram sized for latencyBuckets
+// plus its unbounded overflow bucket. len(latencyBuckets) isn't a constant,
+// so counts can't be a fixed-size array; it's sized here instead of relying
+// on append, so observe can index into it directly.
+func newLatencyHistogram() latencyHistogram {
+	return latencyHistogram{counts: make([]uint64, len(latencyBuckets)+1)}
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	h.count++
+	h.sum += d
+	for i, bound := range latencyBuckets {
+		if d <= bound {
+			h.counts[i]++
+			return
+		}
+	}
+	h.counts[len(latencyBuckets)]++
+}
+
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	out := LatencyHistogram{Count: h.count, Sum: h.sum}
+	for i, bound := range latencyBuckets {
+		out.Buckets = append(out.Buckets, LatencyBucket{UpperBound: bound, Count: h.counts[i]})
+	}
+	out.Buckets = append(out.Buckets, LatencyBucket{Count: h.counts[len(latencyBuckets)]})
+	return out
+}
+
+// LatencyHistogram is a snapshot of a ConcurrentGroup's callback latency
+// distribution, bucketed by upper bound. The final bucket has a zero
+// UpperBound and holds every observation slower than the last real bucket.
+type LatencyHistogram struct {
+	Buckets []LatencyBucket
+	Count uint64
+	Sum time.Duration
+}
+
+// LatencyBucket counts observations at or below UpperBound, not already
+// counted by an earlier bucket. A zero UpperBound marks the unbounded
+// overflow bucket.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count uint64
+}
+
+var (
+	defaultGroupOnce sync.Once
+	defaultGroup *ConcurrentGroup
+)
+
+// defaultConcurrentGroup lazily creates the shared group used by
+// ReadCallback.Concurrent and WriteCallback.Concurrent when no explicit
+// ConcurrentGroup is given, preserving their original zero-configuration
+// behavior.
+func defaultConcurrentGroup() *ConcurrentGroup {
+	defaultGroupOnce.Do(func() {
+		defaultGroup = NewConcurrentGroup(GroupConfig{})
+	})
+	return defaultGroup
+}
+
+// DefaultConcurrentGroup returns the same shared group ReadCallback.Concurrent
+// and WriteCallback.Concurrent dispatch onto. It's exported so other
+// packages, such as reactor/typed, can offer the same zero-configuration
+// Concurrent() behavior without each standing up their own hidden global
+// group.
+func DefaultConcurrentGroup() *ConcurrentGroup {
+	return defaultConcurrentGroup()
+}
@@ -0,0 +1,139 @@
+package reactor
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentGroupRunsQueuedJobs(t *testing.T) {
+	group := NewConcurrentGroup(GroupConfig{})
+	var mu sync.Mutex
+	var count int
+
+	for i := 0; i < 10; i++ {
+		group.dispatch(func() {
+			mu.Lock()
+				count += 1
+			mu.Unlock()
+		})
+	}
+
+	if err := group.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush returned unexpected error: %v", err)
+	}
+
+	if count != 10 {
+		t.Fatalf("Expected count to be 10; got %d", count)
+	}
+}
+
+func TestConcurrentGroupOverflowDropNewest(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{})
+	group := NewConcurrentGroup(GroupConfig{QueueSize: 1, Overflow: OverflowDropNewest})
+
+	// occupy the single worker so nothing drains the queue
+	group.dispatch(func() { close(started); <-block })
+	<-started
+	group.dispatch(func() {}) // fills the queue
+	group.dispatch(func() {}) // dropped: queue is full
+
+	close(block)
+	group.Flush(context.Background())
+
+	if got := group.Stats().Dropped; got != 1 {
+		t.Fatalf("Expected 1 dropped callback; got %d", got)
+	}
+}
+
+func TestConcurrentGroupOverflowDropOldest(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{})
+	group := NewConcurrentGroup(GroupConfig{QueueSize: 1, Overflow: OverflowDropOldest})
+
+	var mu sync.Mutex
+	var ran []int
+	record := func(n int) func() {
+		return func() {
+			mu.Lock()
+				ran = append(ran, n)
+			mu.Unlock()
+		}
+	}
+
+	// occupy the single worker so nothing drains the queue
+	group.dispatch(func() { close(started); <-block })
+	<-started
+	group.dispatch(record(1)) // fills the queue
+	group.dispatch(record(2)) // drops job 1 to make room, then queues itself
+
+	close(block)
+	group.Flush(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ran) != 1 || ran[0] != 2 {
+		t.Fatalf("Expected only the newest job (2) to run; got %v", ran)
+	}
+	if got := group.Stats().Dropped; got != 1 {
+		t.Fatalf("Expected 1 dropped callback; got %d", got)
+	}
+}
+
+func TestConcurrentGroupOverflowError(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{})
+	var mu sync.Mutex
+	var errs int
+	group := NewConcurrentGroup(GroupConfig{
+		QueueSize: 1,
+		Overflow: OverflowError,
+		OnError: func(err error) {
+			mu.Lock()
+				errs += 1
+			mu.Unlock()
+		},
+	})
+
+	group.dispatch(func() { close(started); <-block })
+	<-started
+	group.dispatch(func() {})
+	group.dispatch(func() {})
+
+	close(block)
+	group.Flush(context.Background())
+
+	if errs != 1 {
+		t.Fatalf("Expected OnError to fire once; got %d", errs)
+	}
+}
+
+func TestConcurrentGroupStats(t *testing.T) {
+	group := NewConcurrentGroup(GroupConfig{})
+
+	group.dispatch(func() {})
+	group.Flush(context.Background())
+
+	stats := group.Stats()
+	if stats.Latency.Count != 1 {
+		t.Fatalf("Expected 1 latency observation; got %d", stats.Latency.Count)
+	}
+	if stats.InFlight != 0 {
+		t.Fatalf("Expected no in-flight callbacks after Flush; got %d", stats.InFlight)
+	}
+}
+
+func TestConcurrentGroupFlushRespectsContext(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	group := NewConcurrentGroup(GroupConfig{})
+	group.dispatch(func() { <-block })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := group.Flush(ctx); err == nil {
+		t.Fatal("Expected Flush to return an error for an already-canceled context")
+	}
+}
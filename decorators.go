@@ -0,0 +1,419 @@
+package reactor
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// timerCloser is the io.Closer returned by the time-based decorators below.
+// Closing it stops the decorator's background goroutine and its
+// ticker/timer, so callers running many short-lived decorated callbacks
+// (tests, or a Trigger being torn down) don't leak either.
+type timerCloser struct {
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newTimerCloser() *timerCloser {
+	return &timerCloser{stop: make(chan struct{}), done: make(chan struct{})}
+}
+
+// Close stops the decorator's goroutine and waits for it to exit. Close is
+// safe to call more than once.
+func (c *timerCloser) Close() error {
+	c.once.Do(func() { close(c.stop) })
+	<-c.done
+	return nil
+}
+
+// Debounce returns a ReadCallback that invokes r with the most recent value
+// it was given, once d has passed without a further value arriving. Values
+// that arrive before d elapses reset the quiet period instead of firing r.
+func (r ReadCallback) Debounce(d time.Duration) (ReadCallback, io.Closer) {
+	values := make(chan interface{})
+	closer := newTimerCloser()
+
+	go func() {
+		defer close(closer.done)
+
+		timer := time.NewTimer(d)
+		if !timer.Stop() {
+			<-timer.C
+		}
+
+		var pending interface{}
+		var armed bool
+		for {
+			select {
+			case v := <-values:
+				pending = v
+				armed = true
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(d)
+			case <-timer.C:
+				if armed {
+					r(pending)
+					armed = false
+				}
+			case <-closer.stop:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return func(v interface{}) {
+		select {
+		case values <- v:
+		case <-closer.stop:
+		}
+	}, closer
+}
+
+// Throttle returns a ReadCallback that invokes r immediately for the first
+// value it's given, then ignores values until d has elapsed since that
+// invocation. The next value after the window closes fires immediately and
+// opens a new window.
+func (r ReadCallback) Throttle(d time.Duration) (ReadCallback, io.Closer) {
+	values := make(chan interface{})
+	closer := newTimerCloser()
+
+	go func() {
+		defer close(closer.done)
+
+		ready := true
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		for {
+			select {
+			case v := <-values:
+				if ready {
+					r(v)
+					ready = false
+					timer = time.NewTimer(d)
+					timerC = timer.C
+				}
+			case <-timerC:
+				ready = true
+				timerC = nil
+			case <-closer.stop:
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+		}
+	}()
+
+	return func(v interface{}) {
+		select {
+		case values <- v:
+		case <-closer.stop:
+		}
+	}, closer
+}
+
+// Sample returns a ReadCallback that records the latest value it's given,
+// and invokes r with that value on every tick of d, as long as at least one
+// value has arrived since the previous tick.
+func (r ReadCallback) Sample(d time.Duration) (ReadCallback, io.Closer) {
+	values := make(chan interface{})
+	closer := newTimerCloser()
+
+	go func() {
+		defer close(closer.done)
+
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+
+		var latest interface{}
+		var pending bool
+		for {
+			select {
+			case v := <-values:
+				latest = v
+				pending = true
+			case <-ticker.C:
+				if pending {
+					r(latest)
+					pending = false
+				}
+			case <-closer.stop:
+				return
+			}
+		}
+	}()
+
+	return func(v interface{}) {
+		select {
+		case values <- v:
+		case <-closer.stop:
+		}
+	}, closer
+}
+
+// ReadBatchCallback receives up to n values accumulated by Batch, in the
+// order they arrived.
+type ReadBatchCallback func(values []interface{})
+
+// Batch returns a ReadCallback that accumulates the values it's given and
+// invokes cb with up to n of them at once, flushing early if maxWait elapses
+// since the first value of the pending batch arrived. Close flushes any
+// partial batch immediately before stopping.
+func (cb ReadBatchCallback) Batch(n int, maxWait time.Duration) (ReadCallback, io.Closer) {
+	values := make(chan interface{})
+	closer := newTimerCloser()
+
+	go func() {
+		defer close(closer.done)
+
+		batch := make([]interface{}, 0, n)
+		var timer *time.Timer
+		var timerC <-chan time.Time
+
+		flush := func() {
+			if len(batch) == 0 {
+				return
+			}
+			cb(batch)
+			batch = make([]interface{}, 0, n)
+			if timer != nil {
+				timer.Stop()
+				timerC = nil
+			}
+		}
+
+		for {
+			select {
+			case v := <-values:
+				if len(batch) == 0 {
+					timer = time.NewTimer(maxWait)
+					timerC = timer.C
+				}
+				batch = append(batch, v)
+				if len(batch) >= n {
+					flush()
+				}
+			case <-timerC:
+				flush()
+			case <-closer.stop:
+				flush()
+				return
+			}
+		}
+	}()
+
+	return func(v interface{}) {
+		select {
+		case values <- v:
+		case <-closer.stop:
+		}
+	}, closer
+}
+
+type writeValue struct {
+	prev, v interface{}
+}
+
+// Debounce returns a WriteCallback that invokes w with the most recent
+// prev/v pair it was given, once d has passed without a further pair
+// arriving. Pairs that arrive before d elapses reset the quiet period
+// instead of firing w.
+func (w WriteCallback) Debounce(d time.Duration) (WriteCallback, io.Closer) {
+	values := make(chan writeValue)
+	closer := newTimerCloser()
+
+	go func() {
+		defer close(closer.done)
+
+		timer := time.NewTimer(d)
+		if !timer.Stop() {
+			<-timer.C
+		}
+
+		var pending writeValue
+		var armed bool
+		for {
+			select {
+			case pv := <-values:
+				pending = pv
+				armed = true
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(d)
+			case <-timer.C:
+				if armed {
+					w(pending.prev, pending.v)
+					armed = false
+				}
+			case <-closer.stop:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return func(prev, v interface{}) {
+		select {
+		case values <- writeValue{prev, v}:
+		case <-closer.stop:
+		}
+	}, closer
+}
+
+// Throttle returns a WriteCallback that invokes w immediately for the first
+// prev/v pair it's given, then ignores pairs until d has elapsed since that
+// invocation. The next pair after the window closes fires immediately and
+// opens a new window.
+func (w WriteCallback) Throttle(d time.Duration) (WriteCallback, io.Closer) {
+	values := make(chan writeValue)
+	closer := newTimerCloser()
+
+	go func() {
+		defer close(closer.done)
+
+		ready := true
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		for {
+			select {
+			case pv := <-values:
+				if ready {
+					w(pv.prev, pv.v)
+					ready = false
+					timer = time.NewTimer(d)
+					timerC = timer.C
+				}
+			case <-timerC:
+				ready = true
+				timerC = nil
+			case <-closer.stop:
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+		}
+	}()
+
+	return func(prev, v interface{}) {
+		select {
+		case values <- writeValue{prev, v}:
+		case <-closer.stop:
+		}
+	}, closer
+}
+
+// Sample returns a WriteCallback that records the latest prev/v pair it's
+// given, and invokes w with that pair on every tick of d, as long as at
+// least one pair has arrived since the previous tick.
+func (w WriteCallback) Sample(d time.Duration) (WriteCallback, io.Closer) {
+	values := make(chan writeValue)
+	closer := newTimerCloser()
+
+	go func() {
+		defer close(closer.done)
+
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+
+		var latest writeValue
+		var pending bool
+		for {
+			select {
+			case pv := <-values:
+				latest = pv
+				pending = true
+			case <-ticker.C:
+				if pending {
+					w(latest.prev, latest.v)
+					pending = false
+				}
+			case <-closer.stop:
+				return
+			}
+		}
+	}()
+
+	return func(prev, v interface{}) {
+		select {
+		case values <- writeValue{prev, v}:
+		case <-closer.stop:
+		}
+	}, closer
+}
+
+// WriteBatchEntry is one prev/v pair accumulated by WriteBatchCallback.Batch.
+type WriteBatchEntry struct {
+	Prev, Value interface{}
+}
+
+// WriteBatchCallback receives up to n entries accumulated by Batch, in the
+// order they arrived.
+type WriteBatchCallback func(entries []WriteBatchEntry)
+
+// Batch returns a WriteCallback that accumulates the prev/v pairs it's given
+// and invokes cb with up to n of them at once, flushing early if maxWait
+// elapses since the first entry of the pending batch arrived. Close flushes
+// any partial batch immediately before stopping.
+func (cb WriteBatchCallback) Batch(n int, maxWait time.Duration) (WriteCallback, io.Closer) {
+	values := make(chan WriteBatchEntry)
+	closer := newTimerCloser()
+
+	go func() {
+		defer close(closer.done)
+
+		batch := make([]WriteBatchEntry, 0, n)
+		var timer *time.Timer
+		var timerC <-chan time.Time
+
+		flush := func() {
+			if len(batch) == 0 {
+				return
+			}
+			cb(batch)
+			batch = make([]WriteBatchEntry, 0, n)
+			if timer != nil {
+				timer.Stop()
+				timerC = nil
+			}
+		}
+
+		for {
+			select {
+			case e := <-values:
+				if len(batch) == 0 {
+					timer = time.NewTimer(maxWait)
+					timerC = timer.C
+				}
+				batch = append(batch, e)
+				if len(batch) >= n {
+					flush()
+				}
+			case <-timerC:
+				flush()
+			case <-closer.stop:
+				flush()
+				return
+			}
+		}
+	}()
+
+	return func(prev, v interface{}) {
+		select {
+		case values <- WriteBatchEntry{prev, v}:
+		case <-closer.stop:
+		}
+	}, closer
+}
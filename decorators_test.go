@@ -0,0 +1,303 @@
+package reactor
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadCallbackDebounce(t *testing.T) {
+	var mu sync.Mutex
+	var got []int
+	record := ReadCallback(func(v interface{}) {
+		mu.Lock()
+			got = append(got, v.(int))
+		mu.Unlock()
+	})
+
+	debounced, closer := record.Debounce(20 * time.Millisecond)
+	defer closer.Close()
+
+	debounced(1)
+	debounced(2)
+	debounced(3)
+
+	time.Sleep(60 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0] != 3 {
+		t.Fatalf("Expected a single debounced call with 3; got %v", got)
+	}
+}
+
+func TestReadCallbackThrottle(t *testing.T) {
+	var mu sync.Mutex
+	var got []int
+	record := ReadCallback(func(v interface{}) {
+		mu.Lock()
+			got = append(got, v.(int))
+		mu.Unlock()
+	})
+
+	throttled, closer := record.Throttle(30 * time.Millisecond)
+	defer closer.Close()
+
+	throttled(1)
+	throttled(2)
+	time.Sleep(50 * time.Millisecond)
+	throttled(3)
+
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("Expected throttled calls [1 3]; got %v", got)
+	}
+}
+
+func TestReadCallbackSample(t *testing.T) {
+	var mu sync.Mutex
+	var got []int
+	record := ReadCallback(func(v interface{}) {
+		mu.Lock()
+			got = append(got, v.(int))
+		mu.Unlock()
+	})
+
+	sampled, closer := record.Sample(20 * time.Millisecond)
+	defer closer.Close()
+
+	sampled(1)
+	sampled(2)
+	time.Sleep(30 * time.Millisecond)
+
+	mu.Lock()
+	n := len(got)
+	last := 0
+	if n > 0 {
+		last = got[n-1]
+	}
+	mu.Unlock()
+
+	if n != 1 || last != 2 {
+		t.Fatalf("Expected a single sampled call with the latest value 2; got %v", got)
+	}
+}
+
+func TestReadBatchCallbackBatchBySize(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]interface{}
+	batch := ReadBatchCallback(func(values []interface{}) {
+		mu.Lock()
+			batches = append(batches, values)
+		mu.Unlock()
+	})
+
+	ingest, closer := batch.Batch(3, time.Second)
+	defer closer.Close()
+
+	ingest(1)
+	ingest(2)
+	ingest(3)
+
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Fatalf("Expected one batch of 3 values; got %v", batches)
+	}
+}
+
+func TestReadBatchCallbackFlushesOnMaxWait(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]interface{}
+	batch := ReadBatchCallback(func(values []interface{}) {
+		mu.Lock()
+			batches = append(batches, values)
+		mu.Unlock()
+	})
+
+	ingest, closer := batch.Batch(10, 20*time.Millisecond)
+	defer closer.Close()
+
+	ingest(1)
+	ingest(2)
+
+	time.Sleep(40 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected one partial batch of 2 values; got %v", batches)
+	}
+}
+
+func TestReadBatchCallbackFlushesOnClose(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]interface{}
+	batch := ReadBatchCallback(func(values []interface{}) {
+		mu.Lock()
+			batches = append(batches, values)
+		mu.Unlock()
+	})
+
+	ingest, closer := batch.Batch(10, time.Second)
+	ingest(1)
+	closer.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 || len(batches[0]) != 1 {
+		t.Fatalf("Expected Close to flush the pending batch; got %v", batches)
+	}
+}
+
+func TestWriteCallbackThrottle(t *testing.T) {
+	var mu sync.Mutex
+	var got []int
+	record := WriteCallback(func(prev, v interface{}) {
+		mu.Lock()
+			got = append(got, v.(int))
+		mu.Unlock()
+	})
+
+	throttled, closer := record.Throttle(30 * time.Millisecond)
+	defer closer.Close()
+
+	throttled(nil, 1)
+	throttled(1, 2)
+
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0] != 1 {
+		t.Fatalf("Expected a single throttled call with 1; got %v", got)
+	}
+}
+
+func TestWriteCallbackDebounce(t *testing.T) {
+	var mu sync.Mutex
+	var got []int
+	record := WriteCallback(func(prev, v interface{}) {
+		mu.Lock()
+			got = append(got, v.(int))
+		mu.Unlock()
+	})
+
+	debounced, closer := record.Debounce(20 * time.Millisecond)
+	defer closer.Close()
+
+	debounced(nil, 1)
+	debounced(1, 2)
+	debounced(2, 3)
+
+	time.Sleep(60 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0] != 3 {
+		t.Fatalf("Expected a single debounced call with 3; got %v", got)
+	}
+}
+
+func TestWriteCallbackSample(t *testing.T) {
+	var mu sync.Mutex
+	var got []int
+	record := WriteCallback(func(prev, v interface{}) {
+		mu.Lock()
+			got = append(got, v.(int))
+		mu.Unlock()
+	})
+
+	sampled, closer := record.Sample(20 * time.Millisecond)
+	defer closer.Close()
+
+	sampled(nil, 1)
+	sampled(1, 2)
+	time.Sleep(30 * time.Millisecond)
+
+	mu.Lock()
+	n := len(got)
+	last := 0
+	if n > 0 {
+		last = got[n-1]
+	}
+	mu.Unlock()
+
+	if n != 1 || last != 2 {
+		t.Fatalf("Expected a single sampled call with the latest value 2; got %v", got)
+	}
+}
+
+func TestWriteBatchCallbackBatchBySize(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]WriteBatchEntry
+	batch := WriteBatchCallback(func(entries []WriteBatchEntry) {
+		mu.Lock()
+			batches = append(batches, entries)
+		mu.Unlock()
+	})
+
+	ingest, closer := batch.Batch(3, time.Second)
+	defer closer.Close()
+
+	ingest(nil, 1)
+	ingest(1, 2)
+	ingest(2, 3)
+
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Fatalf("Expected one batch of 3 entries; got %v", batches)
+	}
+}
+
+func TestWriteBatchCallbackFlushesOnMaxWait(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]WriteBatchEntry
+	batch := WriteBatchCallback(func(entries []WriteBatchEntry) {
+		mu.Lock()
+			batches = append(batches, entries)
+		mu.Unlock()
+	})
+
+	ingest, closer := batch.Batch(10, 20*time.Millisecond)
+	defer closer.Close()
+
+	ingest(nil, 1)
+	ingest(1, 2)
+
+	time.Sleep(40 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected one partial batch of 2 entries; got %v", batches)
+	}
+}
+
+func TestWriteBatchCallbackFlushesOnClose(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]WriteBatchEntry
+	batch := WriteBatchCallback(func(entries []WriteBatchEntry) {
+		mu.Lock()
+			batches = append(batches, entries)
+		mu.Unlock()
+	})
+
+	ingest, closer := batch.Batch(10, time.Second)
+	ingest(nil, 1)
+	closer.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 || len(batches[0]) != 1 {
+		t.Fatalf("Expected Close to flush the pending batch; got %v", batches)
+	}
+}
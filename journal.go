@@ -0,0 +1,48 @@
+package reactor
+
+// Journal lets a Trigger durably record its value transitions, so its state
+// can be rebuilt after a restart via Restore. reactor/journal provides a
+// file-backed implementation; callers needing a different backing store can
+// implement Journal directly.
+type Journal interface {
+	// Append durably records a prev -> v transition.
+	Append(prev, v interface{}) error
+	// Replay invokes f once for every transition previously recorded, in the
+	// order they were appended, stopping at the first error f returns.
+	Replay(f func(prev, v interface{}) error) error
+}
+
+// SetJournal sets j as the Journal that SetValue appends every subsequent
+// transition to. Passing nil detaches the current journal, if any.
+func (t *Trigger) SetJournal(j Journal) {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	t.journal = j
+}
+
+// Restore rebuilds t's value by replaying every transition recorded in j,
+// invoking any write callbacks already registered on t as it goes, and then
+// sets j as t's journal so that subsequent SetValue calls continue
+// appending to it. Register write callbacks before calling Restore if they
+// should observe the replayed transitions; Restore does not invoke read
+// callbacks.
+func (t *Trigger) Restore(j Journal) error {
+	err := j.Replay(func(prev, v interface{}) error {
+		t.Lock.Lock()
+			t.value = v
+		t.Lock.Unlock()
+
+		for _,c := range t.writeCallbacks {
+			c(prev, v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	t.Lock.Lock()
+		t.journal = j
+	t.Lock.Unlock()
+	return nil
+}
@@ -0,0 +1,54 @@
+package journal
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// Codec encodes and decodes the prev/v pairs a FileJournal appends. Plug in
+// a custom implementation (e.g. backed by a CBOR library) to change the
+// on-disk format; GobCodec is the default, and JSONCodec is provided for
+// human-readable logs.
+type Codec interface {
+	Encode(w io.Writer, prev, v interface{}) error
+	Decode(r io.Reader) (prev, v interface{}, err error)
+}
+
+// record is the codec-agnostic shape of a single journal entry.
+type record struct {
+	Prev, Value interface{}
+}
+
+// GobCodec encodes entries with encoding/gob. Concrete types used as prev/v
+// must be registered with gob.Register before they're encoded or decoded,
+// the same requirement encoding/gob always has for interface values.
+type GobCodec struct{}
+
+func (GobCodec) Encode(w io.Writer, prev, v interface{}) error {
+	return gob.NewEncoder(w).Encode(&record{prev, v})
+}
+
+func (GobCodec) Decode(r io.Reader) (interface{}, interface{}, error) {
+	var rec record
+	if err := gob.NewDecoder(r).Decode(&rec); err != nil {
+		return nil, nil, err
+	}
+	return rec.Prev, rec.Value, nil
+}
+
+// JSONCodec encodes entries with encoding/json, trading compactness for a
+// human-readable, language-agnostic on-disk format.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(w io.Writer, prev, v interface{}) error {
+	return json.NewEncoder(w).Encode(&record{prev, v})
+}
+
+func (JSONCodec) Decode(r io.Reader) (interface{}, interface{}, error) {
+	var rec record
+	if err := json.NewDecoder(r).Decode(&rec); err != nil {
+		return nil, nil, err
+	}
+	return rec.Prev, rec.Value, nil
+}
@@ -0,0 +1,265 @@
+// Package journal provides a file-backed implementation of reactor.Journal:
+// a write-ahead log of a Trigger's value transitions, with pluggable
+// encoding, size-based rotation, and a configurable fsync policy.
+package journal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FsyncPolicy controls when a FileJournal calls fsync on its active segment.
+type FsyncPolicy int
+
+const (
+	// FsyncAlways fsyncs after every Append.
+	FsyncAlways FsyncPolicy = iota
+	// FsyncInterval fsyncs on a fixed schedule, given by Config.FsyncInterval,
+	// instead of after every Append.
+	FsyncInterval
+	// FsyncNever never fsyncs explicitly, relying on the OS to flush writes
+	// on its own schedule.
+	FsyncNever
+)
+
+// Config configures a FileJournal.
+type Config struct {
+	// Codec encodes and decodes entries. Defaults to GobCodec.
+	Codec Codec
+	// MaxBytes rotates the active segment once it exceeds this size. Zero
+	// disables rotation.
+	MaxBytes int64
+	// Fsync selects when the active segment is flushed to disk.
+	Fsync FsyncPolicy
+	// FsyncInterval is the flush period used when Fsync is FsyncInterval.
+	FsyncInterval time.Duration
+}
+
+// FileJournal is a file-backed reactor.Journal. Entries are appended to an
+// active segment as length-prefixed, codec-encoded records; once the active
+// segment exceeds Config.MaxBytes, it's rotated out and a new one started.
+// Replay reads every rotated segment, oldest first, followed by the active
+// one.
+type FileJournal struct {
+	dir string
+	base string
+	cfg Config
+
+	mu sync.Mutex
+	file *os.File
+	written int64
+	seq int
+
+	stopFsync chan struct{}
+}
+
+// Open opens or creates the journal named base in dir, creating dir if
+// necessary. The returned FileJournal owns its active segment file and
+// should be closed with Close once the caller is done with it.
+func Open(dir, base string, cfg Config) (*FileJournal, error) {
+	if cfg.Codec == nil {
+		cfg.Codec = GobCodec{}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	j := &FileJournal{dir: dir, base: base, cfg: cfg}
+	if err := j.resumeSeq(); err != nil {
+		return nil, err
+	}
+	if err := j.openActive(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Fsync == FsyncInterval {
+		j.startFsyncTicker()
+	}
+	return j, nil
+}
+
+// Close stops the journal's background fsync goroutine, if any, and closes
+// its active segment file.
+func (j *FileJournal) Close() error {
+	if j.stopFsync != nil {
+		close(j.stopFsync)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
+
+// Append encodes the prev -> v transition with j's codec and appends it,
+// length-prefixed, to the active segment, rotating if the segment has grown
+// past Config.MaxBytes.
+func (j *FileJournal) Append(prev, v interface{}) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := j.cfg.Codec.Encode(&buf, prev, v); err != nil {
+		return err
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(buf.Len()))
+
+	if _, err := j.file.Write(length[:]); err != nil {
+		return err
+	}
+	n, err := j.file.Write(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	j.written += int64(len(length)) + int64(n)
+
+	if j.cfg.Fsync == FsyncAlways {
+		if err := j.file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	if j.cfg.MaxBytes > 0 && j.written > j.cfg.MaxBytes {
+		return j.rotate()
+	}
+	return nil
+}
+
+// Replay reads every segment written by j, oldest first, invoking f with
+// each decoded prev/v pair in the order it was appended. Replay stops at
+// the first error f returns.
+func (j *FileJournal) Replay(f func(prev, v interface{}) error) error {
+	paths, err := j.segmentPaths()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if err := j.replaySegment(path, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *FileJournal) activePath() string {
+	return filepath.Join(j.dir, j.base+".log")
+}
+
+func (j *FileJournal) rotatedPath(seq int) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%s.%06d.log", j.base, seq))
+}
+
+func (j *FileJournal) openActive() error {
+	f, err := os.OpenFile(j.activePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	j.file = f
+	j.written = info.Size()
+	return nil
+}
+
+func (j *FileJournal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return err
+	}
+	j.seq++
+	if err := os.Rename(j.activePath(), j.rotatedPath(j.seq)); err != nil {
+		return err
+	}
+	j.written = 0
+	return j.openActive()
+}
+
+// resumeSeq finds the highest rotated segment sequence already on disk, so
+// a reopened journal continues rotating from where it left off instead of
+// overwriting an earlier segment.
+func (j *FileJournal) resumeSeq() error {
+	matches, err := filepath.Glob(filepath.Join(j.dir, j.base+".??????.log"))
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches {
+		var seq int
+		if _, err := fmt.Sscanf(filepath.Base(m), j.base+".%06d.log", &seq); err == nil && seq > j.seq {
+			j.seq = seq
+		}
+	}
+	return nil
+}
+
+func (j *FileJournal) segmentPaths() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(j.dir, j.base+".??????.log"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return append(matches, j.activePath()), nil
+}
+
+func (j *FileJournal) replaySegment(path string, f func(prev, v interface{}) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	for {
+		var length [4]byte
+		if _, err := io.ReadFull(file, length[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		buf := make([]byte, binary.BigEndian.Uint32(length[:]))
+		if _, err := io.ReadFull(file, buf); err != nil {
+			return err
+		}
+
+		prev, v, err := j.cfg.Codec.Decode(bytes.NewReader(buf))
+		if err != nil {
+			return err
+		}
+		if err := f(prev, v); err != nil {
+			return err
+		}
+	}
+}
+
+func (j *FileJournal) startFsyncTicker() {
+	j.stopFsync = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(j.cfg.FsyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				j.mu.Lock()
+				j.file.Sync()
+				j.mu.Unlock()
+			case <-j.stopFsync:
+				return
+			}
+		}
+	}()
+}
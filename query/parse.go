@@ -0,0 +1,315 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lexer turns a query expression into a stream of tokens. It holds no state
+// beyond its position in the input, so it need not be reset between calls.
+type lexer struct {
+	input []rune
+	pos int
+}
+
+func newLexer(expr string) *lexer {
+	return &lexer{input: []rune(expr)}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF}, nil
+	}
+
+	r := l.input[l.pos]
+	switch {
+	case r == '(':
+		l.pos++
+		return token{kind: tokLParen, text: "("}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")"}, nil
+	case r == '"':
+		return l.lexString()
+	case r == '=' || r == '!' || r == '>' || r == '<':
+		return l.lexOp()
+	case unicode.IsDigit(r):
+		return l.lexNumber()
+	case unicode.IsLetter(r) || r == '_':
+		return l.lexIdent()
+	default:
+		return token{}, fmt.Errorf("query: unexpected character %q", r)
+	}
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) lexString() (token, error) {
+	l.pos++ // opening quote
+	start := l.pos
+	for l.pos < len(l.input) && l.input[l.pos] != '"' {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{}, fmt.Errorf("query: unterminated string literal")
+	}
+	text := string(l.input[start:l.pos])
+	l.pos++ // closing quote
+	return token{kind: tokString, text: text}, nil
+}
+
+func (l *lexer) lexOp() (token, error) {
+	start := l.pos
+	l.pos++
+	if l.pos < len(l.input) && l.input[l.pos] == '=' {
+		l.pos++
+	}
+	op := string(l.input[start:l.pos])
+	switch op {
+	case "=", "!=", ">", ">=", "<", "<=":
+		return token{kind: tokOp, text: op}, nil
+	default:
+		return token{}, fmt.Errorf("query: invalid operator %q", op)
+	}
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && (unicode.IsDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.pos++
+	}
+	return token{kind: tokNumber, text: string(l.input[start:l.pos])}, nil
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && (unicode.IsLetter(l.input[l.pos]) || unicode.IsDigit(l.input[l.pos]) || l.input[l.pos] == '_' || l.input[l.pos] == '.') {
+		l.pos++
+	}
+	word := string(l.input[start:l.pos])
+	switch strings.ToUpper(word) {
+	case "AND":
+		return token{kind: tokAnd, text: word}, nil
+	case "OR":
+		return token{kind: tokOr, text: word}, nil
+	case "NOT":
+		return token{kind: tokNot, text: word}, nil
+	default:
+		return token{kind: tokIdent, text: word}, nil
+	}
+}
+
+// parser is a recursive-descent parser over the grammar:
+//
+//	expr       := orExpr
+//	orExpr     := andExpr (OR andExpr)*
+//	andExpr    := unary (AND unary)*
+//	unary      := NOT unary | primary
+//	primary    := "(" expr ")" | comparison
+//	comparison := IDENT op literal
+//	op         := "=" | "!=" | ">" | ">=" | "<" | "<="
+//	literal    := NUMBER | STRING | "true" | "false"
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// Parse compiles a query expression, such as `value > 10 AND type =
+// "sensor"`, into a Query.
+func Parse(expr string) (Query, error) {
+	p := &parser{lex: newLexer(expr)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("query: unexpected token %q", p.tok.text)
+	}
+	return q, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) parseOr() (Query, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := []Query{left}
+	for p.tok.kind == tokOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return Or(terms...), nil
+}
+
+func (p *parser) parseAnd() (Query, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := []Query{left}
+	for p.tok.kind == tokAnd {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return And(terms...), nil
+}
+
+func (p *parser) parseUnary() (Query, error) {
+	if p.tok.kind == tokNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(inner), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Query, error) {
+	if p.tok.kind == tokLParen {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		q, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, fmt.Errorf("query: expected ')', got %q", p.tok.text)
+		}
+		return q, p.advance()
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Query, error) {
+	if p.tok.kind != tokIdent {
+		return nil, fmt.Errorf("query: expected field name, got %q", p.tok.text)
+	}
+	field := p.tok.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokOp {
+		return nil, fmt.Errorf("query: expected comparison operator, got %q", p.tok.text)
+	}
+	op, err := parseOp(p.tok.text)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	value, err := p.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+
+	return Comparison{Field: field, Op: op, Value: value}, nil
+}
+
+func parseOp(text string) (Op, error) {
+	switch text {
+	case "=":
+		return OpEq, nil
+	case "!=":
+		return OpNeq, nil
+	case ">":
+		return OpGt, nil
+	case ">=":
+		return OpGte, nil
+	case "<":
+		return OpLt, nil
+	case "<=":
+		return OpLte, nil
+	default:
+		return 0, fmt.Errorf("query: unknown operator %q", text)
+	}
+}
+
+func (p *parser) parseLiteral() (interface{}, error) {
+	switch p.tok.kind {
+	case tokString:
+		v := p.tok.text
+		return v, p.advance()
+	case tokNumber:
+		f, err := strconv.ParseFloat(p.tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("query: invalid number %q", p.tok.text)
+		}
+		return f, p.advance()
+	case tokIdent:
+		switch p.tok.text {
+		case "true":
+			return true, p.advance()
+		case "false":
+			return false, p.advance()
+		}
+	}
+	return nil, fmt.Errorf("query: expected literal, got %q", p.tok.text)
+}
@@ -0,0 +1,284 @@
+// Package query provides a small declarative predicate language for
+// filtering the values passed to Trigger callbacks, as an alternative to a
+// hand-written Go closure. A Query can be built programmatically with the
+// functions in this file, or parsed from a string with Parse.
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Query is a predicate that can be attached to a Trigger via
+// AddReadCallbackQuery or AddWriteCallbackQuery.
+type Query interface {
+	// Matches reports whether prev and v satisfy the query. prev is nil for
+	// read callbacks, since Trigger.Value has no notion of a previous value.
+	Matches(prev, v interface{}) (bool, error)
+	String() string
+}
+
+// Op is a comparison operator used by a Comparison.
+type Op int
+
+const (
+	OpEq Op = iota
+	OpNeq
+	OpGt
+	OpGte
+	OpLt
+	OpLte
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpEq:
+		return "="
+	case OpNeq:
+		return "!="
+	case OpGt:
+		return ">"
+	case OpGte:
+		return ">="
+	case OpLt:
+		return "<"
+	case OpLte:
+		return "<="
+	default:
+		return "?"
+	}
+}
+
+// Comparison matches when Field's value, read from v, compares to Value
+// using Op. Field may be empty to compare against v itself; any other name,
+// including "value", is looked up on v as a map[string]interface{} or a
+// struct, via reflection. "value" additionally falls back to comparing v
+// itself when v has no such field/key, so it also works against a v with no
+// fields of its own.
+type Comparison struct {
+	Field string
+	Op Op
+	Value interface{}
+}
+
+// Eq builds a Comparison matching field == value.
+func Eq(field string, value interface{}) Comparison { return Comparison{field, OpEq, value} }
+
+// Neq builds a Comparison matching field != value.
+func Neq(field string, value interface{}) Comparison { return Comparison{field, OpNeq, value} }
+
+// Gt builds a Comparison matching field > value.
+func Gt(field string, value interface{}) Comparison { return Comparison{field, OpGt, value} }
+
+// Gte builds a Comparison matching field >= value.
+func Gte(field string, value interface{}) Comparison { return Comparison{field, OpGte, value} }
+
+// Lt builds a Comparison matching field < value.
+func Lt(field string, value interface{}) Comparison { return Comparison{field, OpLt, value} }
+
+// Lte builds a Comparison matching field <= value.
+func Lte(field string, value interface{}) Comparison { return Comparison{field, OpLte, value} }
+
+func (c Comparison) Matches(prev, v interface{}) (bool, error) {
+	fv, ok := fieldValue(v, c.Field)
+	if !ok {
+		return false, fmt.Errorf("query: field %q not found on %T", c.Field, v)
+	}
+	return compare(fv, c.Op, c.Value)
+}
+
+func (c Comparison) String() string {
+	return fmt.Sprintf("%s %s %v", fieldName(c.Field), c.Op, c.Value)
+}
+
+func fieldName(field string) string {
+	if field == "" {
+		return "value"
+	}
+	return field
+}
+
+// andQuery matches when every term matches, short-circuiting on the first
+// false or erroring term.
+type andQuery struct{ terms []Query }
+
+// And builds a Query matching when every one of terms matches.
+func And(terms ...Query) Query { return andQuery{terms} }
+
+func (q andQuery) Matches(prev, v interface{}) (bool, error) {
+	for _, term := range q.terms {
+		ok, err := term.Matches(prev, v)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (q andQuery) String() string { return joinTerms(q.terms, "AND") }
+
+// orQuery matches when any term matches, short-circuiting on the first true
+// term; an erroring term only fails the query if no earlier term matched.
+type orQuery struct{ terms []Query }
+
+// Or builds a Query matching when any one of terms matches.
+func Or(terms ...Query) Query { return orQuery{terms} }
+
+func (q orQuery) Matches(prev, v interface{}) (bool, error) {
+	for _, term := range q.terms {
+		ok, err := term.Matches(prev, v)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (q orQuery) String() string { return joinTerms(q.terms, "OR") }
+
+func joinTerms(terms []Query, sep string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term.String()
+	}
+	return "(" + strings.Join(parts, " "+sep+" ") + ")"
+}
+
+// notQuery matches when its inner query does not.
+type notQuery struct{ q Query }
+
+// Not builds a Query matching when q does not.
+func Not(q Query) Query { return notQuery{q} }
+
+func (n notQuery) Matches(prev, v interface{}) (bool, error) {
+	ok, err := n.q.Matches(prev, v)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
+
+func (n notQuery) String() string { return "NOT " + n.q.String() }
+
+// fieldValue resolves field against v. The empty field name always refers
+// to v itself. Any other name, including "value", is first looked up as a
+// map key (for a map[string]interface{}) or a struct field, via reflection,
+// following pointers down to the value they point at; "value" then falls
+// back to v itself (the pointed-at value, for a pointer) if no such
+// field/key was found, so a Trigger holding a bare int still satisfies a
+// query like "value > 10". A nil pointer resolves no field, not even "value".
+func fieldValue(v interface{}, field string) (interface{}, bool) {
+	if field == "" {
+		return v, true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		mv := rv.MapIndex(reflect.ValueOf(field))
+		if mv.IsValid() {
+			return mv.Interface(), true
+		}
+	case reflect.Struct:
+		fv := rv.FieldByName(strings.Title(field))
+		if !fv.IsValid() {
+			fv = rv.FieldByName(field)
+		}
+		if fv.IsValid() {
+			return fv.Interface(), true
+		}
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, false
+		}
+		return fieldValue(rv.Elem().Interface(), field)
+	}
+
+	if field == "value" {
+		return v, true
+	}
+	return nil, false
+}
+
+// compare applies op to a and b. Numeric kinds are coerced to float64 so
+// that int, float32, float64, and friends all compare sensibly against one
+// another; strings compare lexicographically.
+func compare(a interface{}, op Op, b interface{}) (bool, error) {
+	if op == OpEq || op == OpNeq {
+		eq := reflect.DeepEqual(a, b)
+		if af, aok := toFloat(a); aok {
+			if bf, bok := toFloat(b); bok {
+				eq = af == bf
+			}
+		}
+		if op == OpEq {
+			return eq, nil
+		}
+		return !eq, nil
+	}
+
+	if af, aok := toFloat(a); aok {
+		if bf, bok := toFloat(b); bok {
+			return compareFloat(af, bf, op), nil
+		}
+	}
+
+	if as, aok := a.(string); aok {
+		if bs, bok := b.(string); bok {
+			return compareString(as, bs, op), nil
+		}
+	}
+
+	return false, fmt.Errorf("query: cannot compare %T %s %T", a, op, b)
+}
+
+func compareFloat(a, b float64, op Op) bool {
+	switch op {
+	case OpGt:
+		return a > b
+	case OpGte:
+		return a >= b
+	case OpLt:
+		return a < b
+	case OpLte:
+		return a <= b
+	default:
+		return false
+	}
+}
+
+func compareString(a, b string, op Op) bool {
+	switch op {
+	case OpGt:
+		return a > b
+	case OpGte:
+		return a >= b
+	case OpLt:
+		return a < b
+	case OpLte:
+		return a <= b
+	default:
+		return false
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,161 @@
+package query
+
+import (
+	"testing"
+)
+
+func TestComparisonMatchesValue(t *testing.T) {
+	q := Gt("value", 10.0)
+
+	ok, err := q.Matches(nil, 15)
+	if err != nil {
+		t.Fatalf("Matches returned unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("Expected 15 > 10 to match")
+	}
+
+	ok, err = q.Matches(nil, 5)
+	if err != nil {
+		t.Fatalf("Matches returned unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("Expected 5 > 10 to not match")
+	}
+}
+
+func TestComparisonMatchesMapField(t *testing.T) {
+	q := Eq("type", "sensor")
+	v := map[string]interface{}{"type": "sensor", "value": 42}
+
+	ok, err := q.Matches(nil, v)
+	if err != nil {
+		t.Fatalf("Matches returned unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("Expected type = \"sensor\" to match")
+	}
+}
+
+func TestComparisonMatchesStructField(t *testing.T) {
+	type reading struct {
+		Type string
+		Value int
+	}
+	q := Eq("type", "sensor")
+
+	ok, err := q.Matches(nil, reading{Type: "sensor", Value: 1})
+	if err != nil {
+		t.Fatalf("Matches returned unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("Expected lowercase field name to match exported struct field")
+	}
+}
+
+func TestComparisonUnknownFieldErrors(t *testing.T) {
+	q := Eq("missing", 1)
+
+	if _, err := q.Matches(nil, map[string]interface{}{"value": 1}); err == nil {
+		t.Fatal("Expected an error for an unknown field")
+	}
+}
+
+func TestAndOrNot(t *testing.T) {
+	v := map[string]interface{}{"type": "sensor", "value": 42}
+
+	and := And(Eq("type", "sensor"), Gt("value", 10.0))
+	if ok, err := and.Matches(nil, v); err != nil || !ok {
+		t.Fatalf("And: got (%v, %v); want (true, nil)", ok, err)
+	}
+
+	or := Or(Eq("type", "actuator"), Gt("value", 10.0))
+	if ok, err := or.Matches(nil, v); err != nil || !ok {
+		t.Fatalf("Or: got (%v, %v); want (true, nil)", ok, err)
+	}
+
+	not := Not(Eq("type", "actuator"))
+	if ok, err := not.Matches(nil, v); err != nil || !ok {
+		t.Fatalf("Not: got (%v, %v); want (true, nil)", ok, err)
+	}
+}
+
+func TestParseSimpleComparison(t *testing.T) {
+	q, err := Parse(`value > 10`)
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	ok, err := q.Matches(nil, 15)
+	if err != nil {
+		t.Fatalf("Matches returned unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("Expected value > 10 to match 15")
+	}
+}
+
+func TestParseAndExpression(t *testing.T) {
+	q, err := Parse(`value > 10 AND type = "sensor"`)
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	v := map[string]interface{}{"type": "sensor", "value": 42}
+	ok, err := q.Matches(nil, v)
+	if err != nil {
+		t.Fatalf("Matches returned unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("Expected the AND expression to match")
+	}
+
+	v["type"] = "actuator"
+	ok, err = q.Matches(nil, v)
+	if err != nil {
+		t.Fatalf("Matches returned unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("Expected the AND expression to not match a different type")
+	}
+}
+
+func TestParseParenthesesAndNot(t *testing.T) {
+	q, err := Parse(`NOT (value < 0 OR value > 100)`)
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		v interface{}
+		want bool
+	}{
+		{50, true},
+		{-1, false},
+		{200, false},
+	} {
+		ok, err := q.Matches(nil, tc.v)
+		if err != nil {
+			t.Fatalf("Matches(%v) returned unexpected error: %v", tc.v, err)
+		}
+		if ok != tc.want {
+			t.Errorf("Matches(%v) = %v; want %v", tc.v, ok, tc.want)
+		}
+	}
+}
+
+func TestParseInvalidExpression(t *testing.T) {
+	cases := []string{
+		``,
+		`value >`,
+		`value > 10 AND`,
+		`(value > 10`,
+		`value ~ 10`,
+	}
+
+	for _, expr := range cases {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) expected an error, got none", expr)
+		}
+	}
+}
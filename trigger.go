@@ -1,11 +1,52 @@
 package reactor
 
 import (
+	"context"
 	"sync"
+
+	"github.com/KellenWatt/reactor/query"
 )
 
-// Trigger implements the Initiator interface. Trigger provides a mutex, Lock, 
-// as a convenience for handling shared resources in asynchronous and 
+// ReadCallback is invoked with a Trigger's current value when it's read via
+// Value.
+type ReadCallback func(v interface{})
+
+// WriteCallback is invoked with a Trigger's previous and new values when
+// it's written via SetValue.
+type WriteCallback func(prev, v interface{})
+
+// BindingFunc converts an Initiator's value into the value a bound Binder
+// should take on; see Trigger.AddBinder.
+type BindingFunc func(v interface{}) interface{}
+
+// Initiator is the read side of a binding: anything whose value can be
+// observed and whose changes a Binder can be bound to. Trigger implements
+// Initiator.
+type Initiator interface {
+	Value() interface{}
+	AddBinder(b Binder, f BindingFunc, concurrent bool)
+}
+
+// Binder is the write side of a binding: anything that can take on a value
+// derived from an Initiator. Trigger implements Binder as well, so Triggers
+// can be bound to one another.
+type Binder interface {
+	SetValue(v interface{}) error
+	AddBinding(src Initiator, f BindingFunc)
+}
+
+// Binding records one Initiator -> Binder relationship created by
+// AddBinder: whenever Initiator's value changes, F converts it and, unless
+// Concurrent is true, the result is written to Binder via SetValue.
+type Binding struct {
+	Initiator Initiator
+	Binder Binder
+	F BindingFunc
+	Concurrent bool
+}
+
+// Trigger implements the Initiator interface. Trigger provides a mutex, Lock,
+// as a convenience for handling shared resources in asynchronous and
 // concurrent callbacks.
 type Trigger struct {
 	Lock sync.Mutex
@@ -15,6 +56,14 @@ type Trigger struct {
 	writeCallbacks []WriteCallback
 
 	bindings []Binding
+
+	journal Journal
+
+	closeOnce sync.Once
+	closeCtx context.Context
+	closeCancel context.CancelFunc
+	closed bool
+	wg sync.WaitGroup
 }
 
 // Value returns the value underlying t and runs any callbacks associated with 
@@ -36,15 +85,32 @@ func (t *Trigger) Value() interface{} {
 	return v
 }
 
-// SetValue sets the value underlying t and runs any callbacks associated 
-// with writing. If the current value is nil (for example, if t has not been 
+// SetValue sets the value underlying t and runs any callbacks associated
+// with writing. If the current value is nil (for example, if t has not been
 // set yet), the previous value in callbacks will be nil.
 //
-// The value(s) passed to the callback are as follows, in order: the previous 
+// The value(s) passed to the callback are as follows, in order: the previous
 // value and the new value.
-func (t *Trigger) SetValue(v interface{}) {
+//
+// If t was given a Journal via SetJournal or Restore, the prev -> v
+// transition is durably appended before any write callback runs. If the
+// journal returns an error, SetValue leaves t's value unchanged, runs no
+// callbacks, and returns that error.
+//
+// The previous-value read, the journal append, and the value write all
+// happen under Lock, as a single critical section: this keeps concurrent
+// SetValue calls from interleaving in a way that would let two calls
+// journal the same prev, or let the journaled order disagree with the
+// order t.value actually changed in.
+func (t *Trigger) SetValue(v interface{}) error {
 	t.Lock.Lock()
 		prev := t.value
+		if t.journal != nil {
+			if err := t.journal.Append(prev, v); err != nil {
+				t.Lock.Unlock()
+				return err
+			}
+		}
 		t.value = v
 	t.Lock.Unlock()
 
@@ -58,6 +124,8 @@ func (t *Trigger) SetValue(v interface{}) {
 			b.Binder.SetValue(val)
 		}
 	}
+
+	return nil
 }
 
 // AddBinder adds a Binder to be executed when the value of t changes. If 
@@ -73,15 +141,130 @@ func (t *Trigger) AddBinder(b Binder, f BindingFunc, concurrent bool) {
 	t.bindings = append(t.bindings, Binding{t, b, f, concurrent})
 }
 
+// AddBinding is the Binder-side counterpart to AddBinder: it binds t to src,
+// so that whenever src's value changes, f's result is written to t via
+// SetValue. This reads as "t binds to src" rather than "src binds to t",
+// which is why it's the preferred way to create a binding.
+func (t *Trigger) AddBinding(src Initiator, f BindingFunc) {
+	src.AddBinder(t, f, false)
+}
+
 // AddReadCallback adds a callback that will be run when t is read using Value.
 func (t *Trigger) AddReadCallback(r ReadCallback) {
 	t.readCallbacks = append(t.readCallbacks, r)
 }
 
-// AddWriteCallback adds a callback that will be run when t is written to 
+// AddWriteCallback adds a callback that will be run when t is written to
 // using SetValue.
 func (t *Trigger) AddWriteCallback(w WriteCallback) {
 	t.writeCallbacks = append(t.writeCallbacks, w)
 }
 
+// AddReadCallbackQuery adds r, but only invokes it when q matches the value
+// passed to Value. This turns a declarative query/Query, rather than a
+// hand-written predicate, into the gate Conditional already provides.
+func (t *Trigger) AddReadCallbackQuery(q query.Query, r ReadCallback) {
+	t.AddReadCallback(ReadCallback(r).Conditional(func(v interface{}) bool {
+		ok, err := q.Matches(nil, v)
+		return err == nil && ok
+	}))
+}
+
+// AddWriteCallbackQuery adds w, but only invokes it when q matches the
+// previous and new values passed to SetValue. This turns a declarative
+// query/Query, rather than a hand-written predicate, into the gate
+// Conditional already provides.
+func (t *Trigger) AddWriteCallbackQuery(q query.Query, w WriteCallback) {
+	t.AddWriteCallback(WriteCallback(w).Conditional(func(prev, v interface{}) bool {
+		ok, err := q.Matches(prev, v)
+		return err == nil && ok
+	}))
+}
+
+// context lazily initializes and returns t's shutdown context, so that a
+// zero-value Trigger that never calls Close, AddReadCallbackCtx, or
+// AddWriteCallbackCtx pays nothing for it.
+func (t *Trigger) context() context.Context {
+	t.closeOnce.Do(func() {
+		t.closeCtx, t.closeCancel = context.WithCancel(context.Background())
+	})
+	return t.closeCtx
+}
+
+// AddReadCallbackCtx adds a context-aware read callback, bound to t's own
+// shutdown context. r is tracked as in-flight for the duration of each
+// invocation, so Close can wait for it to finish, and is skipped entirely
+// once t has been closed.
+func (t *Trigger) AddReadCallbackCtx(r ReadCallbackCtx) {
+	ctx := t.context()
+	cb := r.WithContext(ctx)
+	t.AddReadCallback(func(v interface{}) {
+		t.Lock.Lock()
+			if t.closed {
+				t.Lock.Unlock()
+				return
+			}
+			t.wg.Add(1)
+		t.Lock.Unlock()
+
+		defer t.wg.Done()
+		cb(v)
+	})
+}
+
+// AddWriteCallbackCtx adds a context-aware write callback, bound to t's own
+// shutdown context. w is tracked as in-flight for the duration of each
+// invocation, so Close can wait for it to finish, and is skipped entirely
+// once t has been closed.
+func (t *Trigger) AddWriteCallbackCtx(w WriteCallbackCtx) {
+	ctx := t.context()
+	cb := w.WithContext(ctx)
+	t.AddWriteCallback(func(prev, v interface{}) {
+		t.Lock.Lock()
+			if t.closed {
+				t.Lock.Unlock()
+				return
+			}
+			t.wg.Add(1)
+		t.Lock.Unlock()
+
+		defer t.wg.Done()
+		cb(prev, v)
+	})
+}
+
+// Close shuts t down: it cancels the context passed to any callback added
+// with AddReadCallbackCtx/AddWriteCallbackCtx and marks t so that those
+// callbacks are no longer invoked, then blocks until every in-flight one of
+// them finishes or ctx is done, whichever happens first. Close is safe to
+// call more than once; later calls return nil immediately.
+//
+// Close has no effect on callbacks added with AddReadCallback/AddWriteCallback
+// directly, or on the module-global Async/Concurrent goroutines, which are
+// unaffected by any single Trigger's lifecycle.
+func (t *Trigger) Close(ctx context.Context) error {
+	t.Lock.Lock()
+		if t.closed {
+			t.Lock.Unlock()
+			return nil
+		}
+		t.closed = true
+	t.Lock.Unlock()
+
+	t.context()
+	t.closeCancel()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
@@ -1,9 +1,29 @@
 package reactor
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/KellenWatt/reactor/query"
 )
 
+// Indicator is a minimal Binder, used to exercise AddBinder/AddBinding
+// without needing a second Trigger.
+type Indicator struct {
+	value interface{}
+}
+
+func (i *Indicator) SetValue(v interface{}) error {
+	i.value = v
+	return nil
+}
+
+func (i *Indicator) AddBinding(src Initiator, f BindingFunc) {
+	src.AddBinder(i, f, false)
+}
+
 func TestTriggerSetValue(t *testing.T) {
 	var trigger Trigger
 	want := 10
@@ -116,16 +136,17 @@ func TestTriggerConcurrentReadCallback(t *testing.T) {
 	var trigger Trigger
 	var count int
 	wait := make(chan int)
+	group := NewConcurrentGroup(GroupConfig{})
 	conCallback := ReadCallback(func(v interface{}) {
 		trigger.Lock.Lock()
 			count += 1
 		trigger.Lock.Unlock()
 		wait <- count
-	}).Concurrent()
+	}).ConcurrentIn(group)
 
 	trigger.AddReadCallback(conCallback)
-	// stops read concurrency mechanism, to ensure test isolation
-	defer killRead()
+	// group is private to this test, so there's nothing else to isolate from
+	defer group.Flush(context.Background())
 	trigger.Value()
 
 	<-wait
@@ -267,15 +288,16 @@ func TestTriggerConcurrentWriteCallback(t *testing.T) {
 	var trigger Trigger
 	var count int
 	wait := make(chan int)
+	group := NewConcurrentGroup(GroupConfig{})
 	conCallback := WriteCallback(func(prev, v interface{}) {
 		trigger.Lock.Lock()
 			count += 1
 		trigger.Lock.Unlock()
 		wait <- count
-	}).Concurrent()
+	}).ConcurrentIn(group)
 
 	trigger.AddWriteCallback(conCallback)
-	defer killWrite()
+	defer group.Flush(context.Background())
 	trigger.SetValue(1)
 
 	<-wait
@@ -345,7 +367,7 @@ func TestTriggerMultipleConcurrentRead(t *testing.T) {
 
 	t1.AddReadCallback(c1)
 	t2.AddReadCallback(c2)
-	defer killRead()
+	defer defaultConcurrentGroup().Flush(context.Background())
 
 	maxCount := 10
 	for i:=0; i<maxCount; i++ {
@@ -384,7 +406,7 @@ func TestTriggerMultipleConcurrentWrite(t *testing.T) {
 
 	t1.AddWriteCallback(c1)
 	t2.AddWriteCallback(c2)
-	defer killWrite()
+	defer defaultConcurrentGroup().Flush(context.Background())
 
 	maxCount := 10
 	for i:=0; i<maxCount; i++ {
@@ -443,3 +465,208 @@ func TestTriggerCombinedCallbacks(t *testing.T) {
 	}
 }
 
+// memJournal is a minimal in-memory Journal, used to exercise Trigger's
+// journal integration without touching the filesystem.
+type memJournal struct {
+	entries []memEntry
+	appendErr error
+}
+
+type memEntry struct {
+	prev, v interface{}
+}
+
+func (j *memJournal) Append(prev, v interface{}) error {
+	if j.appendErr != nil {
+		return j.appendErr
+	}
+	j.entries = append(j.entries, memEntry{prev, v})
+	return nil
+}
+
+func (j *memJournal) Replay(f func(prev, v interface{}) error) error {
+	for _, e := range j.entries {
+		if err := f(e.prev, e.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTriggerSetJournalAppends(t *testing.T) {
+	var trigger Trigger
+	j := &memJournal{}
+	trigger.SetJournal(j)
+
+	trigger.SetValue(1)
+	trigger.SetValue(2)
+
+	if len(j.entries) != 2 {
+		t.Fatalf("Expected 2 journaled entries; got %d", len(j.entries))
+	}
+	if j.entries[0] != (memEntry{nil, 1}) || j.entries[1] != (memEntry{1, 2}) {
+		t.Fatalf("Unexpected journaled entries: %v", j.entries)
+	}
+}
+
+func TestTriggerSetValueJournalError(t *testing.T) {
+	var trigger Trigger
+	wantErr := errors.New("disk full")
+	j := &memJournal{appendErr: wantErr}
+	trigger.SetJournal(j)
+
+	if err := trigger.SetValue(1); err != wantErr {
+		t.Fatalf("SetValue error = %v; want %v", err, wantErr)
+	}
+	if trigger.Value() != nil {
+		t.Fatalf("Expected value to be left unset after a journal error; got %v", trigger.Value())
+	}
+}
+
+func TestTriggerRestore(t *testing.T) {
+	var trigger Trigger
+	var fired []int
+	trigger.AddWriteCallback(func(prev, v interface{}) {
+		fired = append(fired, v.(int))
+	})
+
+	j := &memJournal{entries: []memEntry{{nil, 1}, {1, 2}, {2, 3}}}
+	if err := trigger.Restore(j); err != nil {
+		t.Fatalf("Restore returned unexpected error: %v", err)
+	}
+
+	if got := trigger.Value(); got != 3 {
+		t.Fatalf("Expected Restore to rebuild value to 3; got %v", got)
+	}
+	if len(fired) != 3 || fired[2] != 3 {
+		t.Fatalf("Expected replayed write callbacks to fire; got %v", fired)
+	}
+
+	// Subsequent writes should continue appending to the restored journal.
+	trigger.SetValue(4)
+	if len(j.entries) != 4 {
+		t.Fatalf("Expected SetValue after Restore to keep journaling; got %d entries", len(j.entries))
+	}
+}
+
+func TestTriggerReadCallbackQuery(t *testing.T) {
+	var trigger Trigger
+	var count int
+	callback := func(v interface{}) {
+		count += 1
+	}
+
+	q, err := query.Parse(`value > 10`)
+	if err != nil {
+		t.Fatalf("query.Parse returned unexpected error: %v", err)
+	}
+
+	trigger.AddReadCallbackQuery(q, callback)
+
+	trigger.SetValue(5)
+	trigger.Value()
+	if count != 0 {
+		t.Fatalf("Expected query to suppress the callback for 5; count = %d", count)
+	}
+
+	trigger.SetValue(15)
+	trigger.Value()
+	if count != 1 {
+		t.Fatalf("Expected query to allow the callback for 15; count = %d", count)
+	}
+}
+
+func TestTriggerWriteCallbackQuery(t *testing.T) {
+	var trigger Trigger
+	var count int
+	callback := func(prev, v interface{}) {
+		count += 1
+	}
+
+	q := query.Eq("value", "sensor")
+	trigger.AddWriteCallbackQuery(q, callback)
+
+	trigger.SetValue("actuator")
+	if count != 0 {
+		t.Fatalf("Expected query to suppress the callback for \"actuator\"; count = %d", count)
+	}
+
+	trigger.SetValue("sensor")
+	if count != 1 {
+		t.Fatalf("Expected query to allow the callback for \"sensor\"; count = %d", count)
+	}
+}
+
+func TestTriggerReadCallbackCtx(t *testing.T) {
+	var trigger Trigger
+	var count int
+	callback := ReadCallbackCtx(func(ctx context.Context, v interface{}) {
+		count += 1
+	})
+
+	trigger.AddReadCallbackCtx(callback)
+	trigger.Value()
+	trigger.Value()
+
+	if count != 2 {
+		t.Fatalf("Expected count to be 2; got %d", count)
+	}
+}
+
+func TestTriggerClose(t *testing.T) {
+	var trigger Trigger
+	var count int
+	callback := ReadCallbackCtx(func(ctx context.Context, v interface{}) {
+		count += 1
+	})
+
+	trigger.AddReadCallbackCtx(callback)
+	trigger.Value()
+
+	if err := trigger.Close(context.Background()); err != nil {
+		t.Fatalf("Close returned unexpected error: %v", err)
+	}
+
+	trigger.Value()
+	if count != 1 {
+		t.Fatalf("Expected Close to refuse further invocations; count = %d, want 1", count)
+	}
+
+	// Close should be idempotent.
+	if err := trigger.Close(context.Background()); err != nil {
+		t.Fatalf("second Close returned unexpected error: %v", err)
+	}
+}
+
+func TestTriggerCloseWaitsForInFlight(t *testing.T) {
+	var trigger Trigger
+	release := make(chan struct{})
+	started := make(chan struct{})
+	callback := ReadCallbackCtx(func(ctx context.Context, v interface{}) {
+		close(started)
+		<-release
+	})
+
+	trigger.AddReadCallbackCtx(callback)
+
+	go trigger.Value()
+	<-started
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- trigger.Close(context.Background())
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("Close returned before in-flight callback finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+
+	if err := <-closed; err != nil {
+		t.Fatalf("Close returned unexpected error: %v", err)
+	}
+}
+
@@ -0,0 +1,72 @@
+package typed
+
+import (
+	"github.com/KellenWatt/reactor"
+)
+
+// Async returns a ReadCallback that runs r in its own goroutine, the same
+// way reactor.ReadCallback.Async does.
+func (r ReadCallback[T]) Async() ReadCallback[T] {
+	return func(v T) {
+		go r(v)
+	}
+}
+
+// ConcurrentIn dispatches r onto g instead of running it inline, the same
+// way reactor.ReadCallback.ConcurrentIn does. Sharing a *reactor.ConcurrentGroup
+// with the untyped API lets typed and untyped Triggers draw from the same
+// worker pool deliberately.
+func (r ReadCallback[T]) ConcurrentIn(g *reactor.ConcurrentGroup) ReadCallback[T] {
+	return func(v T) {
+		g.Submit(func() { r(v) })
+	}
+}
+
+// Concurrent dispatches r onto reactor's shared default ConcurrentGroup, the
+// same way reactor.ReadCallback.Concurrent does. Use ConcurrentIn to size or
+// isolate the worker pool deliberately.
+func (r ReadCallback[T]) Concurrent() ReadCallback[T] {
+	return r.ConcurrentIn(reactor.DefaultConcurrentGroup())
+}
+
+// Conditional returns a ReadCallback that only invokes r when f(v) is true.
+func (r ReadCallback[T]) Conditional(f func(v T) bool) ReadCallback[T] {
+	return func(v T) {
+		if f(v) {
+			r(v)
+		}
+	}
+}
+
+// Async returns a WriteCallback that runs w in its own goroutine, the same
+// way reactor.WriteCallback.Async does.
+func (w WriteCallback[T]) Async() WriteCallback[T] {
+	return func(prev, v T) {
+		go w(prev, v)
+	}
+}
+
+// ConcurrentIn dispatches w onto g instead of running it inline, the same
+// way reactor.WriteCallback.ConcurrentIn does.
+func (w WriteCallback[T]) ConcurrentIn(g *reactor.ConcurrentGroup) WriteCallback[T] {
+	return func(prev, v T) {
+		g.Submit(func() { w(prev, v) })
+	}
+}
+
+// Concurrent dispatches w onto reactor's shared default ConcurrentGroup, the
+// same way reactor.WriteCallback.Concurrent does. Use ConcurrentIn to size or
+// isolate the worker pool deliberately.
+func (w WriteCallback[T]) Concurrent() WriteCallback[T] {
+	return w.ConcurrentIn(reactor.DefaultConcurrentGroup())
+}
+
+// Conditional returns a WriteCallback that only invokes w when f(prev, v)
+// is true.
+func (w WriteCallback[T]) Conditional(f func(prev, v T) bool) WriteCallback[T] {
+	return func(prev, v T) {
+		if f(prev, v) {
+			w(prev, v)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package typed provides a generic, compile-time-checked counterpart to
+// reactor's interface{}-based API. Trigger[T] behaves like reactor.Trigger,
+// but ReadCallback[T]/WriteCallback[T] receive T directly instead of
+// interface{}, removing the v.(T) assertions the untyped API requires.
+package typed
+
+import (
+	"sync"
+)
+
+// ReadCallback is invoked with a Trigger[T]'s current value when it's read
+// via Value.
+type ReadCallback[T any] func(v T)
+
+// WriteCallback is invoked with a Trigger[T]'s previous and new values when
+// it's written via SetValue.
+type WriteCallback[T any] func(prev, v T)
+
+// BindingFunc converts a Trigger[T]'s value into the value a Trigger[U]
+// bound to it should take on; see Bind.
+type BindingFunc[T, U any] func(v T) U
+
+// Trigger is the generic counterpart to reactor.Trigger. Unlike
+// reactor.Trigger, an unset Trigger[T]'s Value is T's zero value rather
+// than nil, since T need not be nil-able.
+type Trigger[T any] struct {
+	Lock sync.Mutex
+	value T
+
+	readCallbacks []ReadCallback[T]
+	writeCallbacks []WriteCallback[T]
+}
+
+// Value returns the value underlying t and runs any callbacks associated
+// with reading.
+//
+// The value(s) passed to the callback are as follows, in order: the current
+// value.
+func (t *Trigger[T]) Value() T {
+	t.Lock.Lock()
+		v := t.value
+	t.Lock.Unlock()
+
+	for _,c := range t.readCallbacks {
+		c(v)
+	}
+
+	return v
+}
+
+// SetValue sets the value underlying t and runs any callbacks associated
+// with writing. If t has not been set yet, the previous value in callbacks
+// will be T's zero value.
+//
+// The value(s) passed to the callback are as follows, in order: the previous
+// value and the new value.
+func (t *Trigger[T]) SetValue(v T) {
+	t.Lock.Lock()
+		prev := t.value
+		t.value = v
+	t.Lock.Unlock()
+
+	for _,c := range t.writeCallbacks {
+		c(prev, v)
+	}
+}
+
+// AddReadCallback adds a callback that will be run when t is read using
+// Value.
+func (t *Trigger[T]) AddReadCallback(r ReadCallback[T]) {
+	t.readCallbacks = append(t.readCallbacks, r)
+}
+
+// AddWriteCallback adds a callback that will be run when t is written to
+// using SetValue.
+func (t *Trigger[T]) AddWriteCallback(w WriteCallback[T]) {
+	t.writeCallbacks = append(t.writeCallbacks, w)
+}
+
+// Bind registers a binding from src to dst: whenever src's value changes,
+// f converts the new value and SetValue is called on dst with the result.
+// Unlike reactor.Binder, this needs no interface implementation from dst;
+// any *Trigger[U] can be a bind target.
+func Bind[T, U any](src *Trigger[T], dst *Trigger[U], f BindingFunc[T, U]) {
+	src.AddWriteCallback(func(prev, v T) {
+		dst.SetValue(f(v))
+	})
+}
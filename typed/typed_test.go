@@ -0,0 +1,123 @@
+package typed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KellenWatt/reactor"
+)
+
+func TestTriggerSetValue(t *testing.T) {
+	var trigger Trigger[int]
+	want := 10
+
+	trigger.SetValue(want)
+
+	if got := trigger.Value(); got != want {
+		t.Fatalf("Value() = %d; want %d", got, want)
+	}
+}
+
+func TestTriggerZeroValue(t *testing.T) {
+	var trigger Trigger[string]
+
+	if got := trigger.Value(); got != "" {
+		t.Fatalf("Expected zero-value string for an unset Trigger[string]; got %q", got)
+	}
+}
+
+func TestTriggerReadCallback(t *testing.T) {
+	var trigger Trigger[int]
+	var count int
+
+	trigger.AddReadCallback(func(v int) {
+		count += 1
+	})
+
+	trigger.Value()
+	trigger.Value()
+
+	if count != 2 {
+		t.Fatalf("Expected count to be 2; got %d", count)
+	}
+}
+
+func TestTriggerWriteCallback(t *testing.T) {
+	var trigger Trigger[int]
+	var prevSeen, vSeen int
+
+	trigger.AddWriteCallback(func(prev, v int) {
+		prevSeen, vSeen = prev, v
+	})
+
+	trigger.SetValue(1)
+	trigger.SetValue(2)
+
+	if prevSeen != 1 || vSeen != 2 {
+		t.Fatalf("Expected last write callback to see (1, 2); got (%d, %d)", prevSeen, vSeen)
+	}
+}
+
+func TestReadCallbackConditional(t *testing.T) {
+	var trigger Trigger[int]
+	var count int
+
+	cb := ReadCallback[int](func(v int) {
+		count += 1
+	}).Conditional(func(v int) bool {
+		return v > 10
+	})
+
+	trigger.AddReadCallback(cb)
+	trigger.SetValue(5)
+	trigger.Value()
+	trigger.SetValue(15)
+	trigger.Value()
+
+	if count != 1 {
+		t.Fatalf("Expected the conditional callback to fire once; got %d", count)
+	}
+}
+
+func TestReadCallbackConcurrentIn(t *testing.T) {
+	group := reactor.NewConcurrentGroup(reactor.GroupConfig{})
+	var trigger Trigger[int]
+	var got int
+
+	cb := ReadCallback[int](func(v int) {
+		got = v
+	}).ConcurrentIn(group)
+
+	trigger.AddReadCallback(cb)
+	trigger.SetValue(42)
+	trigger.Value()
+
+	if err := group.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush returned unexpected error: %v", err)
+	}
+	if got != 42 {
+		t.Fatalf("Expected concurrently dispatched callback to observe 42; got %d", got)
+	}
+}
+
+func TestBind(t *testing.T) {
+	var src Trigger[int]
+	var dst Trigger[string]
+
+	Bind(&src, &dst, func(v int) string {
+		if v > 10 {
+			return "big"
+		}
+		return "small"
+	})
+
+	src.SetValue(1)
+	if got := dst.Value(); got != "small" {
+		t.Fatalf("dst.Value() = %q; want %q", got, "small")
+	}
+
+	src.SetValue(20)
+	if got := dst.Value(); got != "big" {
+		t.Fatalf("dst.Value() = %q; want %q", got, "big")
+	}
+}